@@ -1,53 +1,184 @@
 package oramapper
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
-	"google.golang.org/grpc/grpclog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/golang/protobuf/ptypes"
 	"github.com/golang/protobuf/ptypes/timestamp"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
 	"gopkg.in/oleiade/reflections.v1"
 	ora "gopkg.in/rana/ora.v4"
 )
 
+// instrumentationName identifies this package's spans to OpenTelemetry.
+const instrumentationName = "github.com/mangeshhendre/oramapper"
+
 // Mapper is the struct receiver for the package.
 type Mapper struct {
-	SourceMap    map[string]int
-	TagMap       map[string]string
-	TargetMap    map[string]reflect.StructField
-	TargetStruct *interface{}
-	LastTarget   string
+	SourceMap     map[string]int
+	TagMap        map[string]string
+	TargetMap     map[string]reflect.StructField
+	TargetStruct  *interface{}
+	LastTarget    string
+	NameMapper    NameMapper
+	PathSeparator string
+	Tracer        trace.Tracer
+
+	// planCache holds MapRows's mappingPlan cache (see planCacheKey), keyed
+	// per-Mapper so different Mappers' NameMapper settings can never
+	// collide and share a plan.
+	planCache sync.Map
+}
+
+// WithTracer installs the OpenTelemetry tracer used to instrument
+// SetSource, SetTarget, MapStruct, and MapRows, and returns m for
+// chaining. Without one, mapping operations use the otel global tracer,
+// which is a no-op until a TracerProvider is registered, so existing
+// callers are unaffected.
+func (m *Mapper) WithTracer(tracer trace.Tracer) *Mapper {
+	m.Tracer = tracer
+	return m
+}
+
+// tracer returns the configured Tracer, falling back to the otel global
+// tracer (a no-op absent a registered TracerProvider).
+func (m *Mapper) tracer() trace.Tracer {
+	if m.Tracer != nil {
+		return m.Tracer
+	}
+	return otel.Tracer(instrumentationName)
+}
+
+// NameMapper normalizes a column or field name before it is used as a
+// SourceMap/TargetMap/TagMap key, so differently-tokenized names (Oracle's
+// CUSTOMER_ID versus Go's CustomerId) can still be compared for equality.
+// It is applied to both sides of the mapping, so it must be idempotent and
+// order-independent: NameMapper(NameMapper(x)) == NameMapper(x).
+type NameMapper func(string) string
+
+// SetNameMapper installs the NameMapper used to normalize column and field
+// names. Pass nil to restore the package's historic lowercase-only
+// behavior.
+func (m *Mapper) SetNameMapper(nameMapper NameMapper) {
+	m.NameMapper = nameMapper
+}
+
+// normalize runs raw through the configured NameMapper, falling back to a
+// plain lowercase when none has been set.
+func (m *Mapper) normalize(raw string) string {
+	if m.NameMapper == nil {
+		return strings.ToLower(raw)
+	}
+	return m.NameMapper(raw)
+}
+
+// Identity returns the name unchanged. Useful when columns and fields
+// already agree on tokenization and only exact, case-sensitive matching is
+// wanted.
+func Identity(raw string) string {
+	return raw
+}
+
+// AllCapsUnderscore folds a name into canonical ALL_CAPS_UNDERSCORE form,
+// splitting on lower-to-upper transitions the way Oracle's own column
+// naming does. It is idempotent on names that are already
+// ALL_CAPS_UNDERSCORE, so it can be applied to both CamelCase Go field
+// names and Oracle column names and have them line up: AllCapsUnderscore
+// ("CustomerId") == AllCapsUnderscore("CUSTOMER_ID") == "CUSTOMER_ID".
+func AllCapsUnderscore(raw string) string {
+	var result strings.Builder
+	prevLower := false
+
+	for _, r := range raw {
+		switch {
+		case r >= 'A' && r <= 'Z':
+			if prevLower {
+				result.WriteByte('_')
+			}
+			result.WriteRune(r)
+			prevLower = false
+		case r >= 'a' && r <= 'z':
+			result.WriteRune(r - 32)
+			prevLower = true
+		default:
+			result.WriteRune(r)
+			prevLower = false
+		}
+	}
+
+	return result.String()
+}
+
+// SnakeToCamel folds a name into CamelCase, building on the same word
+// boundaries AllCapsUnderscore detects, so it matches CamelCase Go field
+// names against ALL_CAPS_UNDERSCORE or snake_case Oracle columns:
+// SnakeToCamel("CustomerId") == SnakeToCamel("CUSTOMER_ID") == "CustomerId".
+func SnakeToCamel(raw string) string {
+	segments := strings.Split(strings.ToLower(AllCapsUnderscore(raw)), "_")
+
+	var result strings.Builder
+	for _, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		result.WriteString(strings.ToUpper(segment[:1]))
+		result.WriteString(segment[1:])
+	}
+
+	return result.String()
 }
 
 // New is the initialization for the methods.
 func New() (*Mapper, error) {
 	mapper := Mapper{
-		SourceMap: make(map[string]int),
-		TagMap:    make(map[string]string),
-		TargetMap: make(map[string]reflect.StructField),
+		SourceMap:     make(map[string]int),
+		TagMap:        make(map[string]string),
+		TargetMap:     make(map[string]reflect.StructField),
+		PathSeparator: ".",
 	}
 	return &mapper, nil
 }
 
-func (m *Mapper) SetTarget(target interface{}) error {
+// SetPathSeparator changes the separator MapStruct splits a column's
+// (possibly aliased) name on when resolving it into a nested struct path,
+// e.g. "." for an Oracle alias like ADDRESS.CITY. Defaults to ".".
+func (m *Mapper) SetPathSeparator(separator string) {
+	m.PathSeparator = separator
+}
+
+func (m *Mapper) SetTarget(ctx context.Context, target interface{}) error {
+	_, span := m.tracer().Start(ctx, "oramapper.SetTarget")
+	defer span.End()
+
 	// Third is to extract the fields and structfields
 	// Fourth is to set the fieldmap.
 
 	// First check is to see if the target passed is a valid type for our purposes.
 	if !isValidType(target) {
-		return errors.New("Invalid target type")
+		err := errors.New("Invalid target type")
+		span.RecordError(err)
+		return err
 	}
 
 	// Second check is to get a real copy of the target in the case that it is a pointer.
 	targetValue := reflectValue(target)
 
 	targetType := targetValue.Type()
+	span.SetAttributes(attribute.String("oramapper.target_type", targetType.Name()))
 
 	if m.LastTarget == targetType.Name() {
 		return nil
@@ -59,54 +190,489 @@ func (m *Mapper) SetTarget(target interface{}) error {
 
 	for i := 0; i < targetFieldCount; i++ {
 		field := targetType.Field(i)
-		m.TargetMap[strings.ToLower(field.Name)] = field
+		fieldKey := m.normalize(field.Name)
+		m.TargetMap[fieldKey] = field
+
+		if column := fieldTagColumn(field); column != "" {
+			m.TagMap[m.normalize(column)] = fieldKey
+		}
 	}
 
 	return nil
 }
 
+// fieldTagColumn returns the column name a struct field was tagged with, if
+// any. An explicit `oramap:"EMP_ID"` tag always wins. Failing that, we fall
+// back to the `name=`, then `json=`, component of a `protobuf` tag so that
+// generated gRPC message fields can be mapped without hand-written oramap
+// tags. Returns "" when the field declares no usable tag.
+func fieldTagColumn(field reflect.StructField) string {
+	if tag, ok := field.Tag.Lookup("oramap"); ok && tag != "" {
+		return tag
+	}
+
+	if tag, ok := field.Tag.Lookup("protobuf"); ok {
+		for _, part := range strings.Split(tag, ",") {
+			if strings.HasPrefix(part, "name=") {
+				return strings.TrimPrefix(part, "name=")
+			}
+		}
+		for _, part := range strings.Split(tag, ",") {
+			if strings.HasPrefix(part, "json=") {
+				return strings.TrimPrefix(part, "json=")
+			}
+		}
+	}
+
+	return ""
+}
+
 // Setup your source.  Run immediately after you know the result set is open.
-func (m *Mapper) SetSource(columns []ora.Column) error {
+func (m *Mapper) SetSource(ctx context.Context, columns []ora.Column) error {
+	_, span := m.tracer().Start(ctx, "oramapper.SetSource")
+	defer span.End()
+	span.SetAttributes(attribute.Int("oramapper.column_count", len(columns)))
+
 	for k, v := range columns {
-		m.SourceMap[strings.ToLower(v.Name)] = k
+		m.SourceMap[m.normalizeColumn(v.Name)] = k
 	}
 
 	return nil
 }
 
-func (m *Mapper) MapStruct(row []interface{}, target interface{}) error {
+// normalizeColumn normalizes a source column name for use as a SourceMap
+// key. A dotted (possibly aliased) name like "ADDRESS.CITY" is split on
+// PathSeparator *before* normalizing, and each segment is normalized on
+// its own, rather than running the whole dotted string through a single
+// normalize call. Otherwise a tokenizing NameMapper such as SnakeToCamel
+// would be handed "address.city" as one token and would not reproduce the
+// per-segment normalization MapStruct and buildPlan apply when they later
+// split the same key and resolve it into a nested struct.
+func (m *Mapper) normalizeColumn(raw string) string {
+	if m.PathSeparator == "" || !strings.Contains(raw, m.PathSeparator) {
+		return m.normalize(raw)
+	}
+
+	segments := strings.Split(raw, m.PathSeparator)
+	for i, segment := range segments {
+		segments[i] = m.normalize(segment)
+	}
+	return strings.Join(segments, m.PathSeparator)
+}
+
+func (m *Mapper) MapStruct(ctx context.Context, row []interface{}, target interface{}) error {
+	ctx, span := m.tracer().Start(ctx, "oramapper.MapStruct")
+	defer span.End()
 
 	// For each item we have in the row, look it up in the source map.
 
-	err := m.SetTarget(target)
+	err := m.SetTarget(ctx, target)
 	if err != nil {
+		span.RecordError(err)
 		return errors.New(err.Error())
 	}
 
+	span.SetAttributes(
+		attribute.String("oramapper.target_type", reflectValue(target).Type().Name()),
+		attribute.Int("oramapper.column_count", len(m.SourceMap)),
+	)
+
+	errCount := 0
+
 	for k, v := range m.SourceMap {
 		// Need to see if we have a map in the tags map.  If we do, use that.
 		// If we do not, then need to see if we have a map in the target map.  If we do, use that.
 		// If we do not have a map anywhere, then we do not do anything.
-		// grpclog.Println("Working on", k)
+		if m.PathSeparator != "" && strings.Contains(k, m.PathSeparator) {
+			if err := m.setNestedField(target, strings.Split(k, m.PathSeparator), row[v]); err != nil {
+				span.RecordError(err, trace.WithAttributes(attribute.String("oramapper.column", k)))
+				errCount++
+			}
+			continue
+		}
+
 		targetField, err := m.GetTargetField(k)
 		if err != nil {
-			grpclog.Println(err)
+			span.RecordError(err, trace.WithAttributes(attribute.String("oramapper.column", k)))
+			errCount++
 			continue
 		}
-		r, err := ValueToType(row[v], targetField.Type.Name())
+		r, err := ValueToType(row[v], targetField.Type)
 		if err != nil {
-			grpclog.Println(err)
+			span.RecordError(err, trace.WithAttributes(attribute.String("oramapper.column", k)))
+			errCount++
+			continue
+		}
+		if err := reflections.SetField(target, targetField.Name, r); err != nil {
+			span.RecordError(err, trace.WithAttributes(attribute.String("oramapper.column", k)))
+			errCount++
+		}
+	}
+
+	span.SetAttributes(attribute.Int("oramapper.error_count", errCount))
+
+	return nil
+}
+
+// setNestedField resolves a dotted column path (e.g. ["address", "city"]
+// from an "ADDRESS.CITY" alias) into a nested struct and sets the leaf
+// field, allocating zero-value intermediates for nil pointer-to-struct
+// fields as it descends. A nil value is rejected up front, before any
+// intermediate is allocated, so a NULL nested column doesn't leave behind
+// a spurious empty struct - matching MapRows, which skips nil cells before
+// touching the destination.
+func (m *Mapper) setNestedField(target interface{}, segments []string, value interface{}) error {
+	if value == nil {
+		return ErrNilValue("setNestedField")
+	}
+
+	fieldValue, err := m.resolveNestedField(reflectValue(target), segments)
+	if err != nil {
+		return err
+	}
+
+	r, err := ValueToType(value, fieldValue.Type())
+	if err != nil {
+		return err
+	}
+
+	fieldValue.Set(reflect.ValueOf(r))
+	return nil
+}
+
+// resolveNestedField walks segments level by level into targetValue using
+// reflect, allocating a zero value for any nil pointer-to-struct
+// intermediate it encounters (leaving already-allocated ones alone), and
+// returns the addressable reflect.Value of the leaf field.
+func (m *Mapper) resolveNestedField(targetValue reflect.Value, segments []string) (reflect.Value, error) {
+	current := targetValue
+	seen := make(map[string]bool, len(segments))
+
+	for i, segment := range segments {
+		if seen[segment] {
+			return reflect.Value{}, fmt.Errorf("resolveNestedField: repeated path segment %q", segment)
+		}
+		seen[segment] = true
+
+		if current.Kind() == reflect.Ptr {
+			if current.IsNil() {
+				current.Set(reflect.New(current.Type().Elem()))
+			}
+			current = current.Elem()
+		}
+
+		if current.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("resolveNestedField: %q is not a struct", segment)
+		}
+
+		var field reflect.StructField
+		var ok bool
+		if i == 0 {
+			field, ok = m.TargetMap[segment]
+			if !ok {
+				if tagged, tagOk := m.TagMap[segment]; tagOk {
+					field, ok = m.TargetMap[tagged], true
+				}
+			}
+		} else {
+			field, ok = findStructField(current.Type(), segment, m.normalize)
+		}
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("resolveNestedField: no field for %q", segment)
+		}
+
+		fieldValue := current.FieldByIndex(field.Index)
+
+		if i == len(segments)-1 {
+			if !fieldValue.CanSet() {
+				return reflect.Value{}, fmt.Errorf("resolveNestedField: field %q is unexported", field.Name)
+			}
+			return fieldValue, nil
+		}
+
+		current = fieldValue
+	}
+
+	return reflect.Value{}, errors.New("resolveNestedField: empty path")
+}
+
+// findStructField scans structType's fields for one whose tag (see
+// fieldTagColumn) or name, once run through normalize, equals key.
+func findStructField(structType reflect.Type, key string, normalize func(string) string) (reflect.StructField, bool) {
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		if column := fieldTagColumn(field); column != "" && normalize(column) == key {
+			return field, true
+		}
+		if normalize(field.Name) == key {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+// mappingPlan is a precomputed reflection plan for MapRows: an ordered
+// list of {source column index, destination field-index chain, converter}
+// tuples for one (target type, column set) pair. Applying a plan to a row
+// is a tight loop with no further tag parsing or map lookups.
+type mappingPlan struct {
+	entries []planEntry
+}
+
+type planEntry struct {
+	srcIndex   int
+	fieldIndex []int
+	convert    func(interface{}) (interface{}, error)
+}
+
+// planCacheKey identifies a cached mappingPlan within a single Mapper's
+// planCache: the plan depends on the destination type, the shape (names
+// and order) of the result set, and the Mapper's PathSeparator, since any
+// of the three changing invalidates the field-index chains and source
+// indices baked into the plan. PathSeparator needs to be part of the key
+// even though it doesn't change the columns string itself, because it
+// also controls whether buildPlan treats a column as a nested path or a
+// flat field name.
+//
+// The cache this keys into is a field on Mapper, not a package global:
+// NameMapper also affects field resolution, but NameMapper funcs aren't
+// comparable (and can't be made so reliably - even a code pointer via
+// reflect can collide between distinct closures built from the same
+// factory), so there's no safe way to fold it into a key shared across
+// Mapper instances. Scoping the cache per-Mapper sidesteps that
+// entirely: two Mappers with different NameMappers simply never share a
+// cache to collide in.
+type planCacheKey struct {
+	targetType    reflect.Type
+	columns       string
+	pathSeparator string
+}
+
+// MapRows maps an entire Oracle result set into *[]T or *[]*T in one call.
+// Unlike MapStruct, which re-resolves every column by name on every row,
+// MapRows builds a mappingPlan once per (target type, column set) pair,
+// cached in the Mapper's planCache, and reuses it for every row - turning
+// the per-cell map-lookup-plus-reflections.SetField cost into cached
+// field indices and typed converters.
+func (m *Mapper) MapRows(ctx context.Context, rows [][]interface{}, targetSlicePtr interface{}) error {
+	ctx, span := m.tracer().Start(ctx, "oramapper.MapRows")
+	defer span.End()
+
+	sliceValue := reflect.ValueOf(targetSlicePtr)
+	if sliceValue.Kind() != reflect.Ptr || sliceValue.Elem().Kind() != reflect.Slice {
+		err := errors.New("MapRows: targetSlicePtr must be a pointer to a slice")
+		span.RecordError(err)
+		return err
+	}
+
+	sliceElem := sliceValue.Elem()
+	elemType := sliceElem.Type().Elem()
+
+	isPtrElem := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPtrElem {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		err := errors.New("MapRows: target slice element must be a struct or a pointer to a struct")
+		span.RecordError(err)
+		return err
+	}
+
+	if err := m.SetTarget(ctx, reflect.New(structType).Interface()); err != nil {
+		span.RecordError(err)
+		return err
+	}
+
+	plan := m.plan(ctx, structType)
+
+	span.SetAttributes(
+		attribute.String("oramapper.target_type", structType.Name()),
+		attribute.Int("oramapper.column_count", len(m.SourceMap)),
+		attribute.Int("oramapper.row_count", len(rows)),
+	)
+
+	out := reflect.MakeSlice(sliceElem.Type(), 0, len(rows))
+	errCount := 0
+
+	for _, row := range rows {
+		elem := reflect.New(structType)
+
+		for _, entry := range plan.entries {
+			if entry.srcIndex >= len(row) || row[entry.srcIndex] == nil {
+				continue
+			}
+
+			r, err := entry.convert(row[entry.srcIndex])
+			if err != nil {
+				span.RecordError(err)
+				errCount++
+				continue
+			}
+
+			if err := setByIndexChain(elem.Elem(), entry.fieldIndex, r); err != nil {
+				span.RecordError(err)
+				errCount++
+			}
+		}
+
+		if isPtrElem {
+			out = reflect.Append(out, elem)
+		} else {
+			out = reflect.Append(out, elem.Elem())
+		}
+	}
+
+	span.SetAttributes(attribute.Int("oramapper.error_count", errCount))
+
+	sliceElem.Set(out)
+	return nil
+}
+
+// plan returns the cached mappingPlan for targetType against the Mapper's
+// current SourceMap, building and caching one in m.planCache if this is
+// the first time this (type, column set) pair has been seen on this
+// Mapper.
+func (m *Mapper) plan(ctx context.Context, targetType reflect.Type) *mappingPlan {
+	key := planCacheKey{targetType: targetType, columns: m.columnsFingerprint(), pathSeparator: m.PathSeparator}
+
+	if cached, ok := m.planCache.Load(key); ok {
+		return cached.(*mappingPlan)
+	}
+
+	built := m.buildPlan(ctx, targetType)
+	m.planCache.Store(key, built)
+	return built
+}
+
+// buildPlan resolves every column in the Mapper's SourceMap against
+// targetType, once, into a mappingPlan. Columns with no matching field or
+// no registered converter for the matched field's type are silently
+// skipped, same as MapStruct's per-row behavior.
+func (m *Mapper) buildPlan(ctx context.Context, targetType reflect.Type) *mappingPlan {
+	_, span := m.tracer().Start(ctx, "oramapper.buildPlan")
+	defer span.End()
+
+	plan := &mappingPlan{entries: make([]planEntry, 0, len(m.SourceMap))}
+
+	for column, srcIndex := range m.SourceMap {
+		var fieldType reflect.Type
+		var fieldIndex []int
+
+		if m.PathSeparator != "" && strings.Contains(column, m.PathSeparator) {
+			field, chain, ok := m.resolveFieldIndexChain(targetType, strings.Split(column, m.PathSeparator))
+			if !ok {
+				continue
+			}
+			fieldType, fieldIndex = field.Type, chain
+		} else {
+			field, err := m.GetTargetField(column)
+			if err != nil {
+				continue
+			}
+			fieldType, fieldIndex = field.Type, field.Index
+		}
+
+		convert, ok := valueConverters[fieldType]
+		if !ok {
+			span.RecordError(fmt.Errorf("MapRows: no converter registered for column %q (field type %s)", column, fieldType), trace.WithAttributes(attribute.String("oramapper.column", column)))
 			continue
 		}
-		// fmt.Printf("%v\n%v\n%v\n", target, targetField.Name, r)
-		err = reflections.SetField(target, targetField.Name, r)
 
+		plan.entries = append(plan.entries, planEntry{
+			srcIndex:   srcIndex,
+			fieldIndex: fieldIndex,
+			convert:    convert,
+		})
+	}
+
+	return plan
+}
+
+// resolveFieldIndexChain is resolveNestedField's type-only counterpart: it
+// walks segments across a struct type, rather than a live value, and
+// returns the leaf field plus the full index chain needed to reach it with
+// setByIndexChain. Used once per plan build instead of once per row.
+func (m *Mapper) resolveFieldIndexChain(rootType reflect.Type, segments []string) (reflect.StructField, []int, bool) {
+	currentType := rootType
+	var field reflect.StructField
+	var fieldIndex []int
+	seen := make(map[string]bool, len(segments))
+
+	for i, segment := range segments {
+		if seen[segment] {
+			return reflect.StructField{}, nil, false
+		}
+		seen[segment] = true
+
+		if currentType.Kind() == reflect.Ptr {
+			currentType = currentType.Elem()
+		}
+		if currentType.Kind() != reflect.Struct {
+			return reflect.StructField{}, nil, false
+		}
+
+		var ok bool
+		if i == 0 {
+			field, ok = m.TargetMap[segment]
+			if !ok {
+				if tagged, tagOk := m.TagMap[segment]; tagOk {
+					field, ok = m.TargetMap[tagged], true
+				}
+			}
+		} else {
+			field, ok = findStructField(currentType, segment, m.normalize)
+		}
+		if !ok {
+			return reflect.StructField{}, nil, false
+		}
+
+		fieldIndex = append(fieldIndex, field.Index...)
+		currentType = field.Type
+	}
+
+	return field, fieldIndex, true
+}
+
+// setByIndexChain descends structValue one field index at a time,
+// allocating a zero value for any nil pointer-to-struct intermediate it
+// passes through (leaving already-allocated ones alone), then sets the
+// leaf field it arrives at.
+func setByIndexChain(structValue reflect.Value, fieldIndex []int, value interface{}) error {
+	current := structValue
+
+	for _, idx := range fieldIndex {
+		if current.Kind() == reflect.Ptr {
+			if current.IsNil() {
+				current.Set(reflect.New(current.Type().Elem()))
+			}
+			current = current.Elem()
+		}
+		current = current.Field(idx)
+	}
+
+	if !current.CanSet() {
+		return errors.New("setByIndexChain: field is unexported")
 	}
 
+	current.Set(reflect.ValueOf(value))
 	return nil
 }
 
-func (m Mapper) GetTargetField(key string) (result reflect.StructField, err error) {
+// columnsFingerprint captures the current SourceMap's column set and
+// ordering as a cache key component, so a MapRows plan is rebuilt whenever
+// the result-set shape changes between calls.
+func (m *Mapper) columnsFingerprint() string {
+	names := make([]string, len(m.SourceMap))
+	for name, idx := range m.SourceMap {
+		if idx >= 0 && idx < len(names) {
+			names[idx] = name
+		}
+	}
+	return strings.Join(names, "\x1f")
+}
+
+func (m *Mapper) GetTargetField(key string) (result reflect.StructField, err error) {
 	// First find in the tags.
 	// If found, return that.
 
@@ -151,39 +717,113 @@ func validTypes() []reflect.Kind {
 	return []reflect.Kind{reflect.Struct, reflect.Ptr}
 }
 
-func ValueToType(value interface{}, outputType string) (result interface{}, err error) {
+// valueConverters maps a destination reflect.Type to the function that
+// converts an Oracle result-set cell into that type. Keying by
+// reflect.Type, rather than Type.Name() (which returns "" for pointer,
+// slice, and generic struct types), lets pointer and proto wrapper
+// destinations dispatch correctly.
+var valueConverters = map[reflect.Type]func(interface{}) (interface{}, error){
+	reflect.TypeOf(int64(0)):    func(v interface{}) (interface{}, error) { return RowValueToInt64(v) },
+	reflect.TypeOf(int32(0)):    func(v interface{}) (interface{}, error) { return RowValueToInt32(v) },
+	reflect.TypeOf(uint64(0)):   func(v interface{}) (interface{}, error) { return RowValueToUint64(v) },
+	reflect.TypeOf(uint32(0)):   func(v interface{}) (interface{}, error) { return RowValueToUint32(v) },
+	reflect.TypeOf(float64(0)):  func(v interface{}) (interface{}, error) { return RowValueToFloat64(v) },
+	reflect.TypeOf(float32(0)):  func(v interface{}) (interface{}, error) { return RowValueToFloat32(v) },
+	reflect.TypeOf(""):          func(v interface{}) (interface{}, error) { return RowValueToString(v) },
+	reflect.TypeOf(false):       func(v interface{}) (interface{}, error) { return RowValueToBool(v) },
+	reflect.TypeOf([]byte{}):    func(v interface{}) (interface{}, error) { return RowValueToBytes(v) },
+	reflect.TypeOf(time.Time{}): func(v interface{}) (interface{}, error) { return RowValueToTime(v) },
+
+	reflect.TypeOf((*int64)(nil)):   func(v interface{}) (interface{}, error) { return RowValueToInt64Ptr(v) },
+	reflect.TypeOf((*int32)(nil)):   func(v interface{}) (interface{}, error) { return RowValueToInt32Ptr(v) },
+	reflect.TypeOf((*uint64)(nil)):  func(v interface{}) (interface{}, error) { return RowValueToUint64Ptr(v) },
+	reflect.TypeOf((*uint32)(nil)):  func(v interface{}) (interface{}, error) { return RowValueToUint32Ptr(v) },
+	reflect.TypeOf((*float64)(nil)): func(v interface{}) (interface{}, error) { return RowValueToFloat64Ptr(v) },
+	reflect.TypeOf((*float32)(nil)): func(v interface{}) (interface{}, error) { return RowValueToFloat32Ptr(v) },
+	reflect.TypeOf((*string)(nil)):  func(v interface{}) (interface{}, error) { return RowValueToStringPtr(v) },
+	reflect.TypeOf((*bool)(nil)):    func(v interface{}) (interface{}, error) { return RowValueToBoolPtr(v) },
+
+	reflect.TypeOf((*timestamp.Timestamp)(nil)):    func(v interface{}) (interface{}, error) { return RowValueToTimestamp(v) },
+	reflect.TypeOf((*timestamppb.Timestamp)(nil)):  func(v interface{}) (interface{}, error) { return RowValueToTimestampProto(v) },
+	reflect.TypeOf((*durationpb.Duration)(nil)):    func(v interface{}) (interface{}, error) { return RowValueToDuration(v) },
+	reflect.TypeOf((*wrapperspb.StringValue)(nil)): func(v interface{}) (interface{}, error) { return RowValueToStringValue(v) },
+	reflect.TypeOf((*wrapperspb.Int64Value)(nil)):  func(v interface{}) (interface{}, error) { return RowValueToInt64Value(v) },
+	reflect.TypeOf((*wrapperspb.BoolValue)(nil)):   func(v interface{}) (interface{}, error) { return RowValueToBoolValue(v) },
+
+	reflect.TypeOf(sql.NullString{}):  func(v interface{}) (interface{}, error) { return RowValueToNullString(v) },
+	reflect.TypeOf(sql.NullInt64{}):   func(v interface{}) (interface{}, error) { return RowValueToNullInt64(v) },
+	reflect.TypeOf(sql.NullFloat64{}): func(v interface{}) (interface{}, error) { return RowValueToNullFloat64(v) },
+	reflect.TypeOf(sql.NullBool{}):    func(v interface{}) (interface{}, error) { return RowValueToNullBool(v) },
+}
+
+// ValueToType converts an Oracle result-set cell to outputType, looked up
+// from valueConverters. Returns ErrWhatIsThis if outputType has no
+// registered converter.
+func ValueToType(value interface{}, outputType reflect.Type) (result interface{}, err error) {
 
 	if value == nil {
 		err = ErrNilValue("ValueToType")
 		return
 	}
 
-	switch outputType {
-	case "int64":
-		result, err = RowValueToInt64(value)
-		return result, err
-	case "int32":
-		result, err = RowValueToInt32(value)
-		return result, err
-	case "string":
-		result, err = RowValueToString(value)
-		return result, err
-	case "*time.Timestamp":
-		result, err = RowValueToTimestamp(value)
-		return result, err
+	if convert, ok := valueConverters[outputType]; ok {
+		return convert(value)
 	}
-	// fmt.Printf("outputType is %s\n", outputType)
+
 	err = ErrWhatIsThis("ValueToType", value)
 	return
 }
 
 // RowValueToTimestamp is a function, now shut up.
 func RowValueToTimestamp(value interface{}) (result *timestamp.Timestamp, err error) {
-	result, err = ptypes.TimestampProto(value.(time.Time))
+	t, err := RowValueToTime(value)
 	if err != nil {
-		err = ErrWhatIsThis("RowValueToInt32", value)
+		err = ErrWhatIsThis("RowValueToTimestamp", value)
+		return
+	}
+	result, err = ptypes.TimestampProto(t)
+	if err != nil {
+		err = ErrWhatIsThis("RowValueToTimestamp", value)
+		return
+	}
+	return
+}
+
+// RowValueToTimestampProto converts value to a
+// google.golang.org/protobuf well-known Timestamp.
+func RowValueToTimestampProto(value interface{}) (result *timestamppb.Timestamp, err error) {
+	t, err := RowValueToTime(value)
+	if err != nil {
+		err = ErrWhatIsThis("RowValueToTimestampProto", value)
+		return
+	}
+	result = timestamppb.New(t)
+	return
+}
+
+// RowValueToDuration converts a whole-seconds numeric value to a
+// google.golang.org/protobuf well-known Duration.
+func RowValueToDuration(value interface{}) (result *durationpb.Duration, err error) {
+	seconds, err := RowValueToInt64(value)
+	if err != nil {
+		err = ErrWhatIsThis("RowValueToDuration", value)
 		return
 	}
+	result = durationpb.New(time.Duration(seconds) * time.Second)
+	return
+}
+
+// RowValueToTime will attempt to convert the provided value to a time.Time.
+func RowValueToTime(value interface{}) (result time.Time, err error) {
+	if newvar, ok := value.(time.Time); ok {
+		return newvar, nil
+	}
+
+	if newvar, ok := value.(ora.Date); ok {
+		return newvar.Get(), nil
+	}
+
+	err = ErrWhatIsThis("RowValueToTime", value)
 	return
 }
 
@@ -203,7 +843,7 @@ func RowValueToInt64(value interface{}) (result int64, err error) {
 
 	// No joy
 	result = 0
-	err = ErrWhatIsThis("RowValueToInt32", value)
+	err = ErrWhatIsThis("RowValueToInt64", value)
 	return
 
 }
@@ -220,9 +860,246 @@ func RowValueToInt32(value interface{}) (result int32, err error) {
 	return
 }
 
+// RowValueToUint64 will attempt to convert the provided value to a uint64
+func RowValueToUint64(value interface{}) (result uint64, err error) {
+	if newvar, ok := value.(uint64); ok {
+		result = newvar
+		return
+	}
+
+	if newvar, ok := value.(ora.OCINum); ok {
+		result, err = strconv.ParseUint(newvar.String(), 10, 64)
+		return
+	}
+
+	err = ErrWhatIsThis("RowValueToUint64", value)
+	return
+}
+
+// RowValueToUint32 will attempt to convert the provided value to a uint32
+func RowValueToUint32(value interface{}) (result uint32, err error) {
+	innerResult, err := RowValueToUint64(value)
+	if err != nil {
+		err = ErrWhatIsThis("RowValueToUint32", value)
+		return
+	}
+
+	result = uint32(innerResult)
+	return
+}
+
+// RowValueToFloat64 will attempt to convert the provided value to a float64
+func RowValueToFloat64(value interface{}) (result float64, err error) {
+	if newvar, ok := value.(float64); ok {
+		result = newvar
+		return
+	}
+
+	if newvar, ok := value.(ora.OCINum); ok {
+		result, err = strconv.ParseFloat(newvar.String(), 64)
+		return
+	}
+
+	err = ErrWhatIsThis("RowValueToFloat64", value)
+	return
+}
+
+// RowValueToFloat32 will attempt to convert the provided value to a float32
+func RowValueToFloat32(value interface{}) (result float32, err error) {
+	innerResult, err := RowValueToFloat64(value)
+	if err != nil {
+		err = ErrWhatIsThis("RowValueToFloat32", value)
+		return
+	}
+
+	result = float32(innerResult)
+	return
+}
+
+// RowValueToBool will attempt to convert the provided value to a bool
+func RowValueToBool(value interface{}) (result bool, err error) {
+	if newvar, ok := value.(bool); ok {
+		result = newvar
+		return
+	}
+
+	if newvar, ok := value.(ora.Bool); ok {
+		result = bool(newvar)
+		return
+	}
+
+	err = ErrWhatIsThis("RowValueToBool", value)
+	return
+}
+
+// RowValueToBytes will attempt to convert the provided value to a []byte
+func RowValueToBytes(value interface{}) (result []byte, err error) {
+	if newvar, ok := value.([]byte); ok {
+		result = newvar
+		return
+	}
+
+	if newvar, ok := value.(ora.Raw); ok {
+		result = newvar.Value
+		return
+	}
+
+	err = ErrWhatIsThis("RowValueToBytes", value)
+	return
+}
+
 // RowValueToString will attempt to convert the provided value into a string.
 func RowValueToString(value interface{}) (result string, err error) {
-	result = value.(string)
+	if newvar, ok := value.(string); ok {
+		result = newvar
+		return
+	}
+
+	if newvar, ok := value.(ora.Raw); ok {
+		result = string(newvar.Value)
+		return
+	}
+
+	err = ErrWhatIsThis("RowValueToString", value)
+	return
+}
+
+// RowValueToInt64Ptr is RowValueToInt64, boxed for pointer destinations.
+func RowValueToInt64Ptr(value interface{}) (*int64, error) {
+	result, err := RowValueToInt64(value)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RowValueToInt32Ptr is RowValueToInt32, boxed for pointer destinations.
+func RowValueToInt32Ptr(value interface{}) (*int32, error) {
+	result, err := RowValueToInt32(value)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RowValueToUint64Ptr is RowValueToUint64, boxed for pointer destinations.
+func RowValueToUint64Ptr(value interface{}) (*uint64, error) {
+	result, err := RowValueToUint64(value)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RowValueToUint32Ptr is RowValueToUint32, boxed for pointer destinations.
+func RowValueToUint32Ptr(value interface{}) (*uint32, error) {
+	result, err := RowValueToUint32(value)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RowValueToFloat64Ptr is RowValueToFloat64, boxed for pointer destinations.
+func RowValueToFloat64Ptr(value interface{}) (*float64, error) {
+	result, err := RowValueToFloat64(value)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RowValueToFloat32Ptr is RowValueToFloat32, boxed for pointer destinations.
+func RowValueToFloat32Ptr(value interface{}) (*float32, error) {
+	result, err := RowValueToFloat32(value)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RowValueToStringPtr is RowValueToString, boxed for pointer destinations.
+func RowValueToStringPtr(value interface{}) (*string, error) {
+	result, err := RowValueToString(value)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RowValueToBoolPtr is RowValueToBool, boxed for pointer destinations.
+func RowValueToBoolPtr(value interface{}) (*bool, error) {
+	result, err := RowValueToBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// RowValueToStringValue converts value to a wrapperspb.StringValue.
+func RowValueToStringValue(value interface{}) (*wrapperspb.StringValue, error) {
+	result, err := RowValueToString(value)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.String(result), nil
+}
+
+// RowValueToInt64Value converts value to a wrapperspb.Int64Value.
+func RowValueToInt64Value(value interface{}) (*wrapperspb.Int64Value, error) {
+	result, err := RowValueToInt64(value)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.Int64(result), nil
+}
+
+// RowValueToBoolValue converts value to a wrapperspb.BoolValue.
+func RowValueToBoolValue(value interface{}) (*wrapperspb.BoolValue, error) {
+	result, err := RowValueToBool(value)
+	if err != nil {
+		return nil, err
+	}
+	return wrapperspb.Bool(result), nil
+}
+
+// RowValueToNullString converts value to a sql.NullString.
+func RowValueToNullString(value interface{}) (result sql.NullString, err error) {
+	s, err := RowValueToString(value)
+	if err != nil {
+		return
+	}
+	result = sql.NullString{String: s, Valid: true}
+	return
+}
+
+// RowValueToNullInt64 converts value to a sql.NullInt64.
+func RowValueToNullInt64(value interface{}) (result sql.NullInt64, err error) {
+	i, err := RowValueToInt64(value)
+	if err != nil {
+		return
+	}
+	result = sql.NullInt64{Int64: i, Valid: true}
+	return
+}
+
+// RowValueToNullFloat64 converts value to a sql.NullFloat64.
+func RowValueToNullFloat64(value interface{}) (result sql.NullFloat64, err error) {
+	f, err := RowValueToFloat64(value)
+	if err != nil {
+		return
+	}
+	result = sql.NullFloat64{Float64: f, Valid: true}
+	return
+}
+
+// RowValueToNullBool converts value to a sql.NullBool.
+func RowValueToNullBool(value interface{}) (result sql.NullBool, err error) {
+	b, err := RowValueToBool(value)
+	if err != nil {
+		return
+	}
+	result = sql.NullBool{Bool: b, Valid: true}
 	return
 }
 
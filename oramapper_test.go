@@ -0,0 +1,168 @@
+package oramapper
+
+import (
+	"context"
+	"testing"
+)
+
+type mapRowsAddress struct {
+	City string
+}
+
+type mapRowsTarget struct {
+	ID      int64
+	Name    string
+	Address *mapRowsAddress
+}
+
+func TestMapRows(t *testing.T) {
+	tests := []struct {
+		name     string
+		rows     [][]interface{}
+		wantID   []int64
+		wantName []string
+		wantCity []string
+	}{
+		{
+			name: "basic and nested columns, with a nil cell",
+			rows: [][]interface{}{
+				{int64(1), "Ada", "London"},
+				{int64(2), nil, "Paris"},
+			},
+			wantID:   []int64{1, 2},
+			wantName: []string{"Ada", ""},
+			wantCity: []string{"London", "Paris"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := New()
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			m.SourceMap = map[string]int{
+				"id":           0,
+				"name":         1,
+				"address.city": 2,
+			}
+
+			var targets []mapRowsTarget
+			if err := m.MapRows(context.Background(), tt.rows, &targets); err != nil {
+				t.Fatalf("MapRows() error = %v", err)
+			}
+
+			if len(targets) != len(tt.rows) {
+				t.Fatalf("len(targets) = %d, want %d", len(targets), len(tt.rows))
+			}
+
+			for i, target := range targets {
+				if target.ID != tt.wantID[i] {
+					t.Errorf("targets[%d].ID = %d, want %d", i, target.ID, tt.wantID[i])
+				}
+				if target.Name != tt.wantName[i] {
+					t.Errorf("targets[%d].Name = %q, want %q", i, target.Name, tt.wantName[i])
+				}
+				if target.Address == nil || target.Address.City != tt.wantCity[i] {
+					t.Errorf("targets[%d].Address = %+v, want City %q", i, target.Address, tt.wantCity[i])
+				}
+			}
+		})
+	}
+}
+
+// TestMapRowsPointerElements covers the []*T destination path, which
+// MapRows handles separately from []T (it appends the pointer itself
+// instead of dereferencing it into the slice).
+func TestMapRowsPointerElements(t *testing.T) {
+	m, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	m.SourceMap = map[string]int{"id": 0, "name": 1}
+
+	rows := [][]interface{}{
+		{int64(7), "Grace"},
+	}
+
+	var targets []*mapRowsTarget
+	if err := m.MapRows(context.Background(), rows, &targets); err != nil {
+		t.Fatalf("MapRows() error = %v", err)
+	}
+
+	if len(targets) != 1 || targets[0] == nil {
+		t.Fatalf("targets = %+v", targets)
+	}
+	if targets[0].ID != 7 || targets[0].Name != "Grace" {
+		t.Errorf("targets[0] = %+v, want {ID:7 Name:Grace}", targets[0])
+	}
+}
+
+// TestMapStructNestedNilColumn covers MapStruct's per-row nested path,
+// where a NULL leaf column must not leave behind an allocated-but-empty
+// intermediate struct.
+func TestMapStructNestedNilColumn(t *testing.T) {
+	m, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	m.SourceMap = map[string]int{
+		"id":           0,
+		"address.city": 1,
+	}
+
+	var target mapRowsTarget
+	row := []interface{}{int64(1), nil}
+	if err := m.MapStruct(context.Background(), row, &target); err != nil {
+		t.Fatalf("MapStruct() error = %v", err)
+	}
+
+	if target.ID != 1 {
+		t.Errorf("target.ID = %d, want 1", target.ID)
+	}
+	if target.Address != nil {
+		t.Errorf("target.Address = %+v, want nil for a NULL nested column", target.Address)
+	}
+}
+
+// TestMapRowsPlanCacheKeyPerNameMapper covers the plan cache key: two
+// Mappers mapping the same target type against the same raw column, but
+// with different NameMappers, resolve that column to the target field
+// differently and must not share a cached plan.
+func TestMapRowsPlanCacheKeyPerNameMapper(t *testing.T) {
+	type nameMapperTarget struct {
+		CustomerId int64
+	}
+
+	identity, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	identity.SetNameMapper(Identity)
+	identity.SourceMap = map[string]int{"CUSTOMER_ID": 0}
+
+	allCaps, err := New()
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	allCaps.SetNameMapper(AllCapsUnderscore)
+	allCaps.SourceMap = map[string]int{"CUSTOMER_ID": 0}
+
+	rows := [][]interface{}{{int64(42)}}
+
+	var identityTargets []nameMapperTarget
+	if err := identity.MapRows(context.Background(), rows, &identityTargets); err != nil {
+		t.Fatalf("identity.MapRows() error = %v", err)
+	}
+	if len(identityTargets) != 1 || identityTargets[0].CustomerId != 0 {
+		t.Errorf("identityTargets = %+v, want CustomerId 0 (Identity doesn't fold CUSTOMER_ID into CustomerId)", identityTargets)
+	}
+
+	var allCapsTargets []nameMapperTarget
+	if err := allCaps.MapRows(context.Background(), rows, &allCapsTargets); err != nil {
+		t.Fatalf("allCaps.MapRows() error = %v", err)
+	}
+	if len(allCapsTargets) != 1 || allCapsTargets[0].CustomerId != 42 {
+		t.Errorf("allCapsTargets = %+v, want CustomerId 42 (AllCapsUnderscore folds CUSTOMER_ID into CustomerId) - a cached plan shared with identity would wrongly leave this 0", allCapsTargets)
+	}
+}